@@ -0,0 +1,664 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/tkorri/acpublisher/command"
+	"github.com/tkorri/acpublisher/logger"
+	"github.com/tkorri/acpublisher/pkg/appcenter"
+	"github.com/tkorri/acpublisher/uploader"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const versionString = "1.0.0"
+
+const (
+	uploadCmdString        = "upload"
+	uploadApkCmdString     = "uploadApk"
+	listReleasesCmdString  = "listReleases"
+	showReleaseCmdString   = "showRelease"
+	editReleaseCmdString   = "editRelease"
+	deleteReleaseCmdString = "deleteRelease"
+	downloadAssetCmdString = "downloadAsset"
+	selfupdateCmdString    = "selfupdate"
+	publishCmdString       = "publish"
+)
+
+const (
+	Token             = "token"
+	Owner             = "owner"
+	App               = "app"
+	Binary            = "binary"
+	Apk               = "apk"
+	Mapping           = "mapping"
+	ReleaseNotes      = "releasenotes"
+	ReleaseNotesFile  = "releasenotesfile"
+	Group             = "group"
+	Verbose           = "verbose"
+	Debug             = "debug"
+	ReleaseID         = "release"
+	IncludeDrafts     = "includedrafts"
+	Enabled           = "enabled"
+	DistributionGroup = "distributiongroup"
+	MandatoryUpdate   = "mandatoryupdate"
+	NotifyTesters     = "notifytesters"
+	Asset             = "asset"
+	Output            = "output"
+	Json              = "json"
+	UploadChunkSize   = "uploadchunksize"
+	UploadParallelism = "uploadparallelism"
+	UploadMaxRetries  = "uploadmaxretries"
+	CheckUpdate       = "checkupdate"
+	Channel           = "channel"
+	DryRun            = "dryrun"
+	Config            = "config"
+	Parallelism       = "parallelism"
+	BranchName        = "branchname"
+	CommitHash        = "commithash"
+	CommitMessage     = "commitmessage"
+)
+
+// addCommonFlags registers the flags shared by every command except selfupdate: --checkupdate,
+// which prints a notice when a newer acpublisher release is available, and the logging flags.
+func addCommonFlags(cmd *command.Command) {
+	cmd.AddBool(CheckUpdate, false, "Optional. Check for a newer acpublisher release and print a notice if one is available")
+	cmd.AddBool(Verbose, false, "Optional. Enable verbose logging")
+	cmd.AddBool(Debug, false, "Optional. Enable debug logging")
+}
+
+var log *logger.Logger
+
+func main() {
+	flag.Usage = showHelp
+	flag.CommandLine.SetOutput(os.Stderr)
+
+	// Upload a binary (apk, aab, ipa, zip, msi, appxbundle) to AppCenter
+	uploadCommand := command.New(uploadCmdString)
+	uploadCommand.AddString(Token, "", "Required. Api token for AppCenter")
+	uploadCommand.AddString(Owner, "", "Required. Name of the application owner organization or user. This is can be found from the web url: https://appcenter.ms/users/{owner}/apps/{app} or https://appcenter.ms/orgs/{owner}/apps/{app}")
+	uploadCommand.AddString(App, "", "Required. Application name. This can be found from the web url: https://appcenter.ms/users/{owner}/apps/{app} or https://appcenter.ms/orgs/{owner}/apps/{app}")
+	uploadCommand.AddString(Binary, "", "Required. Path to the binary to upload (.apk, .aab, .ipa, .zip, .msi or .appxbundle)")
+	uploadCommand.AddString(Mapping, "", "Optional. Path to symbol file to upload (ProGuard mapping.txt, dSYM zip or Breakpad syms)")
+	uploadCommand.AddString(ReleaseNotes, "Uploaded with acpublisher", "Optional. Release notes")
+	uploadCommand.AddString(ReleaseNotesFile, "", "Optional. Path to file containing release notes")
+	uploadCommand.AddStringArray(Group, []string{}, "Optional. Id of the group where to distribute this release. Multiple groups can be set with multiple group arguments")
+	uploadCommand.AddInt(UploadChunkSize, int(uploader.DefaultOptions.ChunkSize), "Optional. Size in bytes of each block used to upload the symbol file")
+	uploadCommand.AddInt(UploadParallelism, uploader.DefaultOptions.Parallelism, "Optional. Number of blocks uploaded concurrently")
+	uploadCommand.AddInt(UploadMaxRetries, uploader.DefaultOptions.MaxRetries, "Optional. Number of retries attempted for a failing upload request or block")
+	uploadCommand.AddString(BranchName, "", "Optional. Branch name to record in the release's build metadata")
+	uploadCommand.AddString(CommitHash, "", "Optional. Commit hash to record in the release's build metadata")
+	uploadCommand.AddString(CommitMessage, "", "Optional. Commit message to record in the release's build metadata")
+	addCommonFlags(uploadCommand)
+
+	// uploadApk is kept as a back-compat alias of upload for callers still passing --apk
+	uploadApkCommand := command.New(uploadApkCmdString)
+	uploadApkCommand.AddString(Token, "", "Required. Api token for AppCenter")
+	uploadApkCommand.AddString(Owner, "", "Required. Name of the application owner organization or user. This is can be found from the web url: https://appcenter.ms/users/{owner}/apps/{app} or https://appcenter.ms/orgs/{owner}/apps/{app}")
+	uploadApkCommand.AddString(App, "", "Required. Application name. This can be found from the web url: https://appcenter.ms/users/{owner}/apps/{app} or https://appcenter.ms/orgs/{owner}/apps/{app}")
+	uploadApkCommand.AddString(Apk, "", "Required. Path to apk file to upload")
+	uploadApkCommand.AddString(Mapping, "", "Optional. Path to ProGuard mapping file to upload")
+	uploadApkCommand.AddString(ReleaseNotes, "Uploaded with acpublisher", "Optional. Release notes")
+	uploadApkCommand.AddString(ReleaseNotesFile, "", "Optional. Path to file containing release notes")
+	uploadApkCommand.AddStringArray(Group, []string{}, "Optional. Id of the group where to distribute this release. Multiple groups can be set with multiple group arguments")
+	uploadApkCommand.AddInt(UploadChunkSize, int(uploader.DefaultOptions.ChunkSize), "Optional. Size in bytes of each block used to upload the symbol file")
+	uploadApkCommand.AddInt(UploadParallelism, uploader.DefaultOptions.Parallelism, "Optional. Number of blocks uploaded concurrently")
+	uploadApkCommand.AddInt(UploadMaxRetries, uploader.DefaultOptions.MaxRetries, "Optional. Number of retries attempted for a failing upload request or block")
+	uploadApkCommand.AddString(BranchName, "", "Optional. Branch name to record in the release's build metadata")
+	uploadApkCommand.AddString(CommitHash, "", "Optional. Commit hash to record in the release's build metadata")
+	uploadApkCommand.AddString(CommitMessage, "", "Optional. Commit message to record in the release's build metadata")
+	addCommonFlags(uploadApkCommand)
+
+	// List releases
+	listReleasesCommand := command.New(listReleasesCmdString)
+	listReleasesCommand.AddString(Token, "", "Required. Api token for AppCenter")
+	listReleasesCommand.AddString(Owner, "", "Required. Name of the application owner organization or user")
+	listReleasesCommand.AddString(App, "", "Required. Application name")
+	listReleasesCommand.AddBool(IncludeDrafts, false, "Optional. Include releases that have not been published to any distribution group")
+	listReleasesCommand.AddString(Enabled, "", "Optional. Filter releases by enabled state, \"true\" or \"false\"")
+	listReleasesCommand.AddString(DistributionGroup, "", "Optional. Filter releases by distribution group name")
+	listReleasesCommand.AddBool(Json, false, "Optional. Print output as JSON")
+	addCommonFlags(listReleasesCommand)
+
+	// Show a single release
+	showReleaseCommand := command.New(showReleaseCmdString)
+	showReleaseCommand.AddString(Token, "", "Required. Api token for AppCenter")
+	showReleaseCommand.AddString(Owner, "", "Required. Name of the application owner organization or user")
+	showReleaseCommand.AddString(App, "", "Required. Application name")
+	showReleaseCommand.AddString(ReleaseID, "", "Required. Id of the release to show")
+	showReleaseCommand.AddBool(Json, false, "Optional. Print output as JSON")
+	addCommonFlags(showReleaseCommand)
+
+	// Edit an existing release
+	editReleaseCommand := command.New(editReleaseCmdString)
+	editReleaseCommand.AddString(Token, "", "Required. Api token for AppCenter")
+	editReleaseCommand.AddString(Owner, "", "Required. Name of the application owner organization or user")
+	editReleaseCommand.AddString(App, "", "Required. Application name")
+	editReleaseCommand.AddString(ReleaseID, "", "Required. Id of the release to edit")
+	editReleaseCommand.AddString(ReleaseNotes, "", "Optional. Release notes")
+	editReleaseCommand.AddString(ReleaseNotesFile, "", "Optional. Path to file containing release notes")
+	editReleaseCommand.AddBool(MandatoryUpdate, false, "Optional. Mark the release as a mandatory update")
+	editReleaseCommand.AddBool(NotifyTesters, false, "Optional. Notify testers about the change")
+	editReleaseCommand.AddStringArray(Asset, []string{}, "Optional. Supplementary file to attach as \"path#label\". Multiple assets can be set with multiple asset arguments")
+	addCommonFlags(editReleaseCommand)
+
+	// Delete a release
+	deleteReleaseCommand := command.New(deleteReleaseCmdString)
+	deleteReleaseCommand.AddString(Token, "", "Required. Api token for AppCenter")
+	deleteReleaseCommand.AddString(Owner, "", "Required. Name of the application owner organization or user")
+	deleteReleaseCommand.AddString(App, "", "Required. Application name")
+	deleteReleaseCommand.AddString(ReleaseID, "", "Required. Id of the release to delete")
+	addCommonFlags(deleteReleaseCommand)
+
+	// Download release assets
+	downloadAssetCommand := command.New(downloadAssetCmdString)
+	downloadAssetCommand.AddString(Token, "", "Required. Api token for AppCenter")
+	downloadAssetCommand.AddString(Owner, "", "Required. Name of the application owner organization or user")
+	downloadAssetCommand.AddString(App, "", "Required. Application name")
+	downloadAssetCommand.AddString(ReleaseID, "", "Required. Id of the release whose assets to download")
+	downloadAssetCommand.AddString(Output, "", "Optional. Directory to download assets into. Prints download urls when omitted")
+	downloadAssetCommand.AddBool(Json, false, "Optional. Print output as JSON")
+	addCommonFlags(downloadAssetCommand)
+
+	// Update acpublisher itself from GitHub Releases
+	selfupdateCommand := command.New(selfupdateCmdString)
+	selfupdateCommand.AddString(Channel, ChannelStable, "Optional. Release channel to check, \"stable\" or \"prerelease\"")
+	selfupdateCommand.AddBool(DryRun, false, "Optional. Print the newest version and release notes without downloading or installing it")
+	selfupdateCommand.AddBool(Verbose, false, "Optional. Enable verbose logging")
+	selfupdateCommand.AddBool(Debug, false, "Optional. Enable debug logging")
+
+	// Publish multiple apps described by a config file
+	publishCommand := command.New(publishCmdString)
+	publishCommand.AddString(Config, "", "Required. Path to a YAML (or JSON) file describing the apps to publish")
+	publishCommand.AddInt(Parallelism, 4, "Optional. Number of apps published concurrently")
+	publishCommand.AddString(BranchName, "", "Optional. Branch name to record in each release's build metadata")
+	publishCommand.AddString(CommitHash, "", "Optional. Commit hash to record in each release's build metadata")
+	publishCommand.AddString(CommitMessage, "", "Optional. Commit message to record in each release's build metadata")
+	addCommonFlags(publishCommand)
+
+	if len(os.Args) < 2 {
+		showHelp()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case uploadCommand.Name:
+		handleUploadCommand(uploadCommand, Binary)
+	case uploadApkCommand.Name:
+		handleUploadCommand(uploadApkCommand, Apk)
+	case listReleasesCommand.Name:
+		handleListReleasesCommand(listReleasesCommand)
+	case showReleaseCommand.Name:
+		handleShowReleaseCommand(showReleaseCommand)
+	case editReleaseCommand.Name:
+		handleEditReleaseCommand(editReleaseCommand)
+	case deleteReleaseCommand.Name:
+		handleDeleteReleaseCommand(deleteReleaseCommand)
+	case downloadAssetCommand.Name:
+		handleDownloadAssetCommand(downloadAssetCommand)
+	case selfupdateCommand.Name:
+		handleSelfupdateCommand(selfupdateCommand)
+	case publishCommand.Name:
+		handlePublishCommand(publishCommand)
+	default:
+		showHelp()
+		os.Exit(1)
+	}
+}
+
+func showHelp() {
+	logger.Errorln("acpublisher %s", versionString)
+	logger.Errorln("Usage: %s <command> [<args>]", os.Args[0])
+	logger.Errorln("Supported commands")
+	logger.Errorln("    %s\tUpload a binary to AppCenter", uploadCmdString)
+	logger.Errorln("    %s\tUpload an apk to AppCenter (deprecated, use %s)", uploadApkCmdString, uploadCmdString)
+	logger.Errorln("    %s\tList releases of an app", listReleasesCmdString)
+	logger.Errorln("    %s\tShow details of a release", showReleaseCmdString)
+	logger.Errorln("    %s\tEdit a release", editReleaseCmdString)
+	logger.Errorln("    %s\tDelete a release", deleteReleaseCmdString)
+	logger.Errorln("    %s\tDownload the assets of a release", downloadAssetCmdString)
+	logger.Errorln("    %s\tUpdate acpublisher to the latest release", selfupdateCmdString)
+	logger.Errorln("    %s\tPublish multiple apps described by a config file", publishCmdString)
+}
+
+func showCommandHelp(command *command.Command) {
+	logger.Errorln("acpublisher %s", versionString)
+	logger.Errorln("Usage: %s %s [<args>]", os.Args[0], command.Name)
+	logger.Errorln("Supported arguments")
+	command.Command.PrintDefaults()
+}
+
+func handleVersionCommand() {
+	logger.Println("acpublisher %s", versionString)
+}
+
+// parseCommand parses the command line arguments for cmd and sets up logging. It exits
+// the process on a parse error or when no arguments were given.
+func parseCommand(cmd *command.Command) {
+	err := cmd.Command.Parse(os.Args[2:])
+	if err != nil {
+		logger.Errorln("Unrecognized parameters:\n%s", err)
+		showCommandHelp(cmd)
+		os.Exit(1)
+	}
+
+	log = logger.New(cmd.GetBool(Verbose), cmd.GetBool(Verbose) || cmd.GetBool(Debug))
+
+	if len(os.Args[2:]) == 0 {
+		showCommandHelp(cmd)
+		os.Exit(1)
+	}
+}
+
+// requireAppSlug validates that Owner and App were given and returns the "owner/app" slug.
+func requireAppSlug(cmd *command.Command) string {
+	if cmd.GetString(Owner) == "" {
+		log.E("Owner is required")
+		os.Exit(1)
+	}
+
+	if cmd.GetString(App) == "" {
+		log.E("App is required")
+		os.Exit(1)
+	}
+
+	return cmd.GetString(Owner) + "/" + cmd.GetString(App)
+}
+
+// requireReleaseId validates that ReleaseID was given and returns it.
+func requireReleaseId(cmd *command.Command) string {
+	if cmd.GetString(ReleaseID) == "" {
+		log.E("Release is required")
+		os.Exit(1)
+	}
+
+	return cmd.GetString(ReleaseID)
+}
+
+// newClient builds an appcenter.Client for cmd, wired to the command's log.
+func newClient(cmd *command.Command) *appcenter.Client {
+	return appcenter.New(cmd.GetString(Token), appcenter.WithLogger(log))
+}
+
+func handleUploadCommand(upload *command.Command, binaryFlag string) {
+	parseCommand(upload)
+	if upload.GetBool(CheckUpdate) {
+		checkForUpdate(log, ChannelStable)
+	}
+
+	appSlug := requireAppSlug(upload)
+	client := newClient(upload)
+	ctx := context.Background()
+
+	// Check that the binary is available and figure out its build type from the extension
+	if upload.GetString(binaryFlag) == "" {
+		log.E("%s is required", binaryFlag)
+		os.Exit(1)
+	}
+
+	binaryFile, err := os.Open(upload.GetString(binaryFlag))
+	if err != nil {
+		log.E("Cannot open binary file:\n%s", err)
+		os.Exit(1)
+	}
+	defer binaryFile.Close()
+
+	buildType, err := appcenter.DetectBuildType(binaryFile.Name())
+	if err != nil {
+		log.E("Cannot determine build type:\n%s", err)
+		os.Exit(1)
+	}
+	log.D("Detected build type %s", buildType)
+
+	if upload.GetInt(UploadChunkSize) < 1 {
+		log.E("%s must be a positive number of bytes", UploadChunkSize)
+		os.Exit(1)
+	}
+	if upload.GetInt(UploadParallelism) < 1 {
+		log.E("%s must be at least 1", UploadParallelism)
+		os.Exit(1)
+	}
+
+	uploadOpts := uploader.Options{
+		ChunkSize:   int64(upload.GetInt(UploadChunkSize)),
+		Parallelism: upload.GetInt(UploadParallelism),
+		MaxRetries:  upload.GetInt(UploadMaxRetries),
+	}
+
+	// Setup release notes
+	var releaseNotes = upload.GetString(ReleaseNotes)
+	if upload.GetString(ReleaseNotesFile) != "" {
+		notes, err := ioutil.ReadFile(upload.GetString(ReleaseNotesFile))
+		if err != nil {
+			log.E("Cannot read release notes file contents:\n%s", err)
+			os.Exit(1)
+		}
+		releaseNotes = string(notes)
+	}
+
+	// Check that mapping file is available if one is set, and determine its symbol type
+	// up front so an unsupported mapping file is rejected before the release goes live.
+	var mappingFile *os.File = nil
+	var symbolType appcenter.SymbolType
+	if upload.GetString(Mapping) != "" {
+		mappingFile, err = os.Open(upload.GetString(Mapping))
+		if err != nil {
+			log.E("Cannot open mapping file:\n%s", err)
+			os.Exit(1)
+		}
+		defer mappingFile.Close()
+
+		symbolType, err = appcenter.DetectSymbolType(mappingFile.Name())
+		if err != nil {
+			log.E("Cannot determine symbol type:\n%s", err)
+			os.Exit(1)
+		}
+		log.D("Detected symbol type %s", symbolType)
+	}
+
+	// Create release
+	log.I("Creating new release...")
+	begin, err := client.BeginReleaseUpload(ctx, appSlug, buildType)
+	if err != nil {
+		log.E("Release FAILED\n%s", err)
+		os.Exit(1)
+	}
+	err = uploader.UploadRelease(ctx, begin.UploadUrl, binaryFile, uploadOpts)
+	if err != nil {
+		log.E("Release FAILED\n%s", err)
+		os.Exit(1)
+	}
+	response, err := client.CommitRelease(ctx, appSlug, begin.UploadId)
+	if err != nil {
+		log.E("Release FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	var build *buildMetadata
+	if upload.GetString(BranchName) != "" || upload.GetString(CommitHash) != "" || upload.GetString(CommitMessage) != "" {
+		build = &buildMetadata{
+			BranchName:    upload.GetString(BranchName),
+			CommitHash:    upload.GetString(CommitHash),
+			CommitMessage: upload.GetString(CommitMessage),
+		}
+	}
+
+	_, err = client.UpdateRelease(ctx, appSlug, response.ReleaseId, appcenter.ReleaseUpdateRequest{ReleaseNotes: releaseNotes, Build: build})
+	if err != nil {
+		log.E("Release FAILED")
+		os.Exit(1)
+	}
+	log.I("Release %s OK", response.ReleaseId)
+
+	// Publish release to groups
+	if len(upload.GetStringArray(Group)) > 0 {
+		log.I("Publishing release %s to group(s)...", response.ReleaseId)
+		for _, group := range upload.GetStringArray(Group) {
+			_, err = client.PublishRelease(ctx, appSlug, response.ReleaseId, "groups", appcenter.ReleaseDestinationRequest{Id: group})
+			if err != nil {
+				log.E("Publishing FAILED\n%s", err)
+				os.Exit(1)
+			}
+		}
+		log.I("Publish OK")
+	} else {
+		log.D("No groups defined, skipping publish")
+	}
+
+	// If mapping file is set and available then proceed with mapping upload
+	if mappingFile != nil {
+		log.I("Uploading mapping file...")
+
+		release, err := client.GetRelease(ctx, appSlug, response.ReleaseId)
+		if err != nil {
+			log.E("Uploading FAILED\n%s", err)
+			os.Exit(1)
+		}
+		begin, err := client.BeginSymbolUpload(ctx, appSlug, release.ShortVersion, release.Version, mappingFile.Name(), symbolType)
+		if err != nil {
+			log.E("Uploading FAILED\n%s", err)
+			os.Exit(1)
+		}
+
+		err = uploader.UploadSymbols(ctx, begin.UploadUrl, mappingFile, uploadOpts)
+		if err != nil {
+			_, _ = client.CommitSymbols(ctx, appSlug, begin.SymbolUploadId, appcenter.ABORTED)
+			log.E("Uploading FAILED\n%s", err)
+			os.Exit(1)
+		}
+
+		_, err = client.CommitSymbols(ctx, appSlug, begin.SymbolUploadId, appcenter.COMMITTED)
+		if err != nil {
+			log.E("Uploading FAILED\n%s", err)
+			os.Exit(1)
+		}
+		log.I("Mapping upload OK")
+	} else {
+		log.D("No mapping file defined, skipping mapping file upload")
+	}
+}
+
+func handleListReleasesCommand(cmd *command.Command) {
+	parseCommand(cmd)
+	if cmd.GetBool(CheckUpdate) {
+		checkForUpdate(log, ChannelStable)
+	}
+	appSlug := requireAppSlug(cmd)
+	client := newClient(cmd)
+
+	var filter *appcenter.ReleaseListFilter
+	if cmd.GetString(Enabled) != "" || cmd.GetString(DistributionGroup) != "" {
+		filter = &appcenter.ReleaseListFilter{DistributionGroup: cmd.GetString(DistributionGroup)}
+		if cmd.GetString(Enabled) != "" {
+			enabled, err := strconv.ParseBool(cmd.GetString(Enabled))
+			if err != nil {
+				log.E("Enabled must be \"true\" or \"false\"")
+				os.Exit(1)
+			}
+			filter.Enabled = &enabled
+		}
+	}
+
+	releases, err := client.ListReleases(context.Background(), appSlug, cmd.GetBool(IncludeDrafts), filter)
+	if err != nil {
+		log.E("Listing releases FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	if cmd.GetBool(Json) {
+		printJson(releases)
+		return
+	}
+
+	for _, release := range releases {
+		logger.Println("%d\t%s (%s)\tenabled=%t", release.Id, release.Version, release.ShortVersion, release.Enabled)
+	}
+}
+
+func handleShowReleaseCommand(cmd *command.Command) {
+	parseCommand(cmd)
+	if cmd.GetBool(CheckUpdate) {
+		checkForUpdate(log, ChannelStable)
+	}
+	appSlug := requireAppSlug(cmd)
+	releaseId := requireReleaseId(cmd)
+	client := newClient(cmd)
+
+	release, err := client.GetRelease(context.Background(), appSlug, releaseId)
+	if err != nil {
+		log.E("Showing release FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	if cmd.GetBool(Json) {
+		printJson(release)
+		return
+	}
+
+	logger.Println("Id:            %d", release.Id)
+	logger.Println("App:           %s (%s)", release.AppDisplayName, release.AppName)
+	logger.Println("Version:       %s (%s)", release.Version, release.ShortVersion)
+	logger.Println("Uploaded at:   %s", release.UploadedAt)
+	logger.Println("Enabled:       %t", release.Enabled)
+}
+
+func handleEditReleaseCommand(cmd *command.Command) {
+	parseCommand(cmd)
+	if cmd.GetBool(CheckUpdate) {
+		checkForUpdate(log, ChannelStable)
+	}
+	appSlug := requireAppSlug(cmd)
+	releaseId := requireReleaseId(cmd)
+	client := newClient(cmd)
+
+	var releaseNotes = cmd.GetString(ReleaseNotes)
+	if cmd.GetString(ReleaseNotesFile) != "" {
+		notes, err := ioutil.ReadFile(cmd.GetString(ReleaseNotesFile))
+		if err != nil {
+			log.E("Cannot read release notes file contents:\n%s", err)
+			os.Exit(1)
+		}
+		releaseNotes = string(notes)
+	}
+
+	request := appcenter.ReleaseUpdateRequest{
+		ReleaseNotes:    releaseNotes,
+		MandatoryUpdate: cmd.GetBool(MandatoryUpdate),
+		NotifyTesters:   cmd.GetBool(NotifyTesters),
+	}
+
+	if assets := cmd.GetStringArray(Asset); len(assets) > 0 {
+		parsedAssets := make([]struct {
+			Label    string `json:"label,omitempty"`
+			FileName string `json:"file_name,omitempty"`
+		}, 0, len(assets))
+		for _, asset := range assets {
+			path, label, err := parseAsset(asset)
+			if err != nil {
+				log.E("Invalid asset %q:\n%s", asset, err)
+				os.Exit(1)
+			}
+			parsedAssets = append(parsedAssets, struct {
+				Label    string `json:"label,omitempty"`
+				FileName string `json:"file_name,omitempty"`
+			}{Label: label, FileName: filepath.Base(path)})
+		}
+		request.Assets = &parsedAssets
+	}
+
+	response, err := client.UpdateRelease(context.Background(), appSlug, releaseId, request)
+	if err != nil {
+		log.E("Editing release FAILED\n%s", err)
+		os.Exit(1)
+	}
+	log.I("Release %s updated", releaseId)
+
+	if cmd.GetBool(Json) {
+		printJson(response)
+	}
+}
+
+// parseAsset splits a "path#label" argument into its path and label parts.
+func parseAsset(asset string) (path string, label string, err error) {
+	parts := strings.SplitN(asset, "#", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("expected format path#label")
+	}
+	if _, err := os.Stat(parts[0]); err != nil {
+		return "", "", err
+	}
+	return parts[0], parts[1], nil
+}
+
+func handleDeleteReleaseCommand(cmd *command.Command) {
+	parseCommand(cmd)
+	if cmd.GetBool(CheckUpdate) {
+		checkForUpdate(log, ChannelStable)
+	}
+	appSlug := requireAppSlug(cmd)
+	releaseId := requireReleaseId(cmd)
+	client := newClient(cmd)
+
+	err := client.DeleteRelease(context.Background(), appSlug, releaseId)
+	if err != nil {
+		log.E("Deleting release FAILED\n%s", err)
+		os.Exit(1)
+	}
+	log.I("Release %s deleted", releaseId)
+}
+
+func handleDownloadAssetCommand(cmd *command.Command) {
+	parseCommand(cmd)
+	if cmd.GetBool(CheckUpdate) {
+		checkForUpdate(log, ChannelStable)
+	}
+	appSlug := requireAppSlug(cmd)
+	releaseId := requireReleaseId(cmd)
+	client := newClient(cmd)
+
+	downloads, err := client.ListReleaseDownloads(context.Background(), appSlug, releaseId)
+	if err != nil {
+		log.E("Listing release assets FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	outputDir := cmd.GetString(Output)
+	if outputDir == "" {
+		if cmd.GetBool(Json) {
+			printJson(downloads)
+			return
+		}
+		for _, download := range downloads {
+			logger.Println("%s\t%s", download.Name, download.Url)
+		}
+		return
+	}
+
+	for _, download := range downloads {
+		log.I("Downloading %s...", download.Name)
+		if err := downloadAssetFile(download.Url, filepath.Join(outputDir, download.Name)); err != nil {
+			log.E("Downloading %s FAILED\n%s", download.Name, err)
+			os.Exit(1)
+		}
+	}
+	log.I("Download OK")
+}
+
+func downloadAssetFile(url string, destination string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(fmt.Sprintf("Unexpected response from server: %d", resp.StatusCode))
+	}
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
+
+// printJson prints v as indented JSON to stdout.
+func printJson(v interface{}) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.E("Cannot render JSON output:\n%s", err)
+		os.Exit(1)
+	}
+	logger.Println("%s", string(out))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseAsset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "changelog.txt")
+	if err := ioutil.WriteFile(path, []byte("changes"), 0644); err != nil {
+		t.Fatalf("failed to set up test file: %s", err)
+	}
+
+	gotPath, gotLabel, err := parseAsset(path + "#Changelog")
+	if err != nil {
+		t.Fatalf("parseAsset() returned unexpected error: %s", err)
+	}
+	if gotPath != path || gotLabel != "Changelog" {
+		t.Errorf("parseAsset() = (%q, %q), want (%q, %q)", gotPath, gotLabel, path, "Changelog")
+	}
+
+	if _, _, err := parseAsset(path); err == nil {
+		t.Error("parseAsset() without a \"#\" separator returned nil, want an error")
+	}
+
+	if _, _, err := parseAsset("#Changelog"); err == nil {
+		t.Error("parseAsset() with an empty path returned nil, want an error")
+	}
+
+	if _, _, err := parseAsset(path + "#"); err == nil {
+		t.Error("parseAsset() with an empty label returned nil, want an error")
+	}
+
+	if _, _, err := parseAsset(filepath.Join(dir, "missing.txt") + "#Changelog"); err == nil {
+		t.Error("parseAsset() for a file that does not exist returned nil, want an error")
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/tkorri/acpublisher/command"
+	"github.com/tkorri/acpublisher/pkg/appcenter"
+	"github.com/tkorri/acpublisher/uploader"
+	"gopkg.in/yaml.v3"
+)
+
+// buildMetadata is the anonymous struct type embedded as ReleaseUpdateRequest.Build.
+type buildMetadata = struct {
+	BranchName    string `json:"branch_name,omitempty"`
+	CommitHash    string `json:"commit_hash,omitempty"`
+	CommitMessage string `json:"commit_message,omitempty"`
+}
+
+// PublishConfig describes the apps a single "publish" invocation should release. It is
+// loaded from YAML (JSON is valid YAML, so plain JSON config files also work).
+type PublishConfig struct {
+	Apps []AppConfig `yaml:"apps"`
+}
+
+// AppConfig is one app entry in a PublishConfig.
+type AppConfig struct {
+	Name             string             `yaml:"name,omitempty"`
+	Token            string             `yaml:"token"`
+	Owner            string             `yaml:"owner"`
+	App              string             `yaml:"app"`
+	Binary           string             `yaml:"binary"`
+	Mapping          string             `yaml:"mapping,omitempty"`
+	ReleaseNotes     string             `yaml:"release_notes,omitempty"`
+	ReleaseNotesFile string             `yaml:"release_notes_file,omitempty"`
+	Destinations     []DestinationConfig `yaml:"destinations,omitempty"`
+}
+
+// DestinationConfig is a single distribution group an app's release is published to,
+// mirroring appcenter.ReleaseDestinationRequest. Like the upload command, publish only
+// targets the "groups" destination type.
+type DestinationConfig struct {
+	Id              string `yaml:"id,omitempty"`
+	Name            string `yaml:"name,omitempty"`
+	MandatoryUpdate bool   `yaml:"mandatory_update,omitempty"`
+	NotifyTesters   bool   `yaml:"notify_testers,omitempty"`
+}
+
+// PublishResult reports the outcome of publishing a single app from a PublishConfig.
+type PublishResult struct {
+	App        string `json:"app"`
+	Status     string `json:"status"`
+	ReleaseId  string `json:"release_id,omitempty"`
+	ReleaseUrl string `json:"release_url,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func loadConfig(path string) (*PublishConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := PublishConfig{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func handlePublishCommand(cmd *command.Command) {
+	parseCommand(cmd)
+	if cmd.GetBool(CheckUpdate) {
+		checkForUpdate(log, ChannelStable)
+	}
+
+	if cmd.GetString(Config) == "" {
+		log.E("Config is required")
+		os.Exit(1)
+	}
+
+	config, err := loadConfig(cmd.GetString(Config))
+	if err != nil {
+		log.E("Reading config FAILED\n%s", err)
+		os.Exit(1)
+	}
+	if len(config.Apps) == 0 {
+		log.E("Config does not define any apps")
+		os.Exit(1)
+	}
+
+	var build *buildMetadata
+	if cmd.GetString(BranchName) != "" || cmd.GetString(CommitHash) != "" || cmd.GetString(CommitMessage) != "" {
+		build = &buildMetadata{
+			BranchName:    cmd.GetString(BranchName),
+			CommitHash:    cmd.GetString(CommitHash),
+			CommitMessage: cmd.GetString(CommitMessage),
+		}
+	}
+
+	parallelism := cmd.GetInt(Parallelism)
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	log.I("Publishing %d app(s) with parallelism %d...", len(config.Apps), parallelism)
+	results := publishApps(context.Background(), config.Apps, build, parallelism)
+	printJson(results)
+
+	for _, result := range results {
+		if result.Status != "ok" {
+			os.Exit(1)
+		}
+	}
+}
+
+// publishApps releases every app in apps, running at most parallelism releases at once,
+// and returns one PublishResult per app in the same order as apps.
+func publishApps(ctx context.Context, apps []AppConfig, build *buildMetadata, parallelism int) []PublishResult {
+	results := make([]PublishResult, len(apps))
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, app := range apps {
+		wg.Add(1)
+		go func(i int, app AppConfig) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i] = publishApp(ctx, app, build)
+		}(i, app)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func publishApp(ctx context.Context, app AppConfig, build *buildMetadata) PublishResult {
+	result := PublishResult{App: app.Name}
+	if result.App == "" {
+		result.App = app.Owner + "/" + app.App
+	}
+
+	if app.Token == "" || app.Owner == "" || app.App == "" || app.Binary == "" {
+		result.Status = "error"
+		result.Error = "app entry requires token, owner, app and binary"
+		return result
+	}
+
+	appSlug := app.Owner + "/" + app.App
+	client := appcenter.New(app.Token, appcenter.WithLogger(log))
+
+	binaryFile, err := os.Open(app.Binary)
+	if err != nil {
+		return publishError(result, err)
+	}
+	defer binaryFile.Close()
+
+	buildType, err := appcenter.DetectBuildType(binaryFile.Name())
+	if err != nil {
+		return publishError(result, err)
+	}
+
+	releaseNotes := app.ReleaseNotes
+	if app.ReleaseNotesFile != "" {
+		notes, err := ioutil.ReadFile(app.ReleaseNotesFile)
+		if err != nil {
+			return publishError(result, err)
+		}
+		releaseNotes = string(notes)
+	}
+
+	begin, err := client.BeginReleaseUpload(ctx, appSlug, buildType)
+	if err != nil {
+		return publishError(result, err)
+	}
+
+	if err := uploader.UploadRelease(ctx, begin.UploadUrl, binaryFile, uploader.DefaultOptions); err != nil {
+		return publishError(result, err)
+	}
+
+	commit, err := client.CommitRelease(ctx, appSlug, begin.UploadId)
+	if err != nil {
+		return publishError(result, err)
+	}
+
+	if _, err := client.UpdateRelease(ctx, appSlug, commit.ReleaseId, appcenter.ReleaseUpdateRequest{
+		ReleaseNotes: releaseNotes,
+		Build:        build,
+	}); err != nil {
+		return publishError(result, err)
+	}
+
+	for _, destination := range app.Destinations {
+		if _, err := client.PublishRelease(ctx, appSlug, commit.ReleaseId, "groups", appcenter.ReleaseDestinationRequest{
+			Id:              destination.Id,
+			Name:            destination.Name,
+			MandatoryUpdate: destination.MandatoryUpdate,
+			NotifyTesters:   destination.NotifyTesters,
+		}); err != nil {
+			return publishError(result, err)
+		}
+	}
+
+	if app.Mapping != "" {
+		if err := publishSymbols(ctx, client, appSlug, commit.ReleaseId, app.Mapping); err != nil {
+			return publishError(result, err)
+		}
+	}
+
+	result.Status = "ok"
+	result.ReleaseId = commit.ReleaseId
+	result.ReleaseUrl = commit.ReleaseUrl
+	return result
+}
+
+func publishSymbols(ctx context.Context, client *appcenter.Client, appSlug string, releaseId string, mappingPath string) error {
+	mappingFile, err := os.Open(mappingPath)
+	if err != nil {
+		return err
+	}
+	defer mappingFile.Close()
+
+	symbolType, err := appcenter.DetectSymbolType(mappingFile.Name())
+	if err != nil {
+		return err
+	}
+
+	release, err := client.GetRelease(ctx, appSlug, releaseId)
+	if err != nil {
+		return err
+	}
+
+	begin, err := client.BeginSymbolUpload(ctx, appSlug, release.ShortVersion, release.Version, mappingFile.Name(), symbolType)
+	if err != nil {
+		return err
+	}
+
+	if err := uploader.UploadSymbols(ctx, begin.UploadUrl, mappingFile, uploader.DefaultOptions); err != nil {
+		_, _ = client.CommitSymbols(ctx, appSlug, begin.SymbolUploadId, appcenter.ABORTED)
+		return err
+	}
+
+	_, err = client.CommitSymbols(ctx, appSlug, begin.SymbolUploadId, appcenter.COMMITTED)
+	return err
+}
+
+func publishError(result PublishResult, err error) PublishResult {
+	result.Status = "error"
+	result.Error = err.Error()
+	return result
+}
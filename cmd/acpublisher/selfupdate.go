@@ -0,0 +1,321 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/tkorri/acpublisher/command"
+	"github.com/tkorri/acpublisher/logger"
+)
+
+const (
+	githubOwner        = "tkorri"
+	githubRepo         = "acpublisher"
+	githubReleasesUrl  = "https://api.github.com/repos/" + githubOwner + "/" + githubRepo + "/releases"
+	checksumsAssetName = "checksums.txt"
+
+	ChannelStable     = "stable"
+	ChannelPrerelease = "prerelease"
+)
+
+// githubRelease is the subset of the GitHub releases API response acpublisher needs.
+type githubRelease struct {
+	TagName    string        `json:"tag_name"`
+	Name       string        `json:"name"`
+	Body       string        `json:"body"`
+	Prerelease bool          `json:"prerelease"`
+	Assets     []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadUrl string `json:"browser_download_url"`
+}
+
+// latestRelease returns the newest GitHub release for channel: "stable" uses GitHub's
+// own latest-release endpoint (which already excludes prereleases), while "prerelease"
+// lists all releases (newest first) and returns the newest one flagged Prerelease,
+// falling back to the newest release overall if none is marked as a prerelease.
+func latestRelease(channel string) (*githubRelease, error) {
+	if channel == ChannelStable {
+		release := githubRelease{}
+		if err := getJson(githubReleasesUrl+"/latest", &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+
+	var releases []githubRelease
+	if err := getJson(githubReleasesUrl, &releases); err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, errors.New("no releases found")
+	}
+	for i := range releases {
+		if releases[i].Prerelease {
+			return &releases[i], nil
+		}
+	}
+	return &releases[0], nil
+}
+
+func getJson(url string, v interface{}) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(fmt.Sprintf("Unexpected response from GitHub: %d", resp.StatusCode))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// isNewerVersion reports whether tag (a GitHub release tag, e.g. "v1.2.0" or
+// "v1.2.0-rc.1") is newer than current (callers pass versionString). Each is split into its
+// dot-separated numeric release part and an optional "-prerelease" suffix; release
+// parts are compared numerically first, and if those are equal a prerelease is treated
+// as older than the final release it precedes, matching semver precedence. A malformed
+// tag is treated as not newer.
+func isNewerVersion(current string, tag string) bool {
+	currentRelease, currentPre := splitVersion(current)
+	tagRelease, tagPre := splitVersion(tag)
+
+	if cmp := compareReleaseParts(currentRelease, tagRelease); cmp != 0 {
+		return cmp < 0
+	}
+
+	if currentPre == tagPre {
+		return false
+	}
+	if currentPre == "" {
+		return false // current is the final release; tag is a prerelease of the same version
+	}
+	if tagPre == "" {
+		return true // tag finalized the release current is still a prerelease of
+	}
+	return tagPre > currentPre
+}
+
+// splitVersion splits v ("v1.2.0-rc.1") into its release part ("1.2.0") and prerelease
+// suffix ("rc.1"), dropping the leading "v".
+func splitVersion(v string) (release string, prerelease string) {
+	v = strings.TrimPrefix(v, "v")
+	if idx := strings.IndexByte(v, '-'); idx >= 0 {
+		return v[:idx], v[idx+1:]
+	}
+	return v, ""
+}
+
+// compareReleaseParts compares two dot-separated numeric release parts, returning
+// -1, 0 or 1 the way strings.Compare does.
+func compareReleaseParts(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			fmt.Sscanf(aParts[i], "%d", &x)
+		}
+		if i < len(bParts) {
+			fmt.Sscanf(bParts[i], "%d", &y)
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// platformAssetName is the release asset name expected for the running platform,
+// e.g. "acpublisher_linux_amd64".
+func platformAssetName() string {
+	name := fmt.Sprintf("%s_%s_%s", githubRepo, runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func findAsset(assets []githubAsset, name string) *githubAsset {
+	for i := range assets {
+		if assets[i].Name == name {
+			return &assets[i]
+		}
+	}
+	return nil
+}
+
+func downloadAsset(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("Unexpected response from server: %d", resp.StatusCode))
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// verifyChecksum downloads the release's checksums.txt (sha256sum format, one
+// "<hex digest>  <asset name>" line per asset) and confirms data matches assetName.
+func verifyChecksum(assets []githubAsset, assetName string, data []byte) error {
+	checksumsAsset := findAsset(assets, checksumsAssetName)
+	if checksumsAsset == nil {
+		return errors.New("release does not publish " + checksumsAssetName)
+	}
+
+	checksums, err := downloadAsset(checksumsAsset.BrowserDownloadUrl)
+	if err != nil {
+		return err
+	}
+
+	var wantDigest string
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == assetName {
+			wantDigest = fields[0]
+			break
+		}
+	}
+	if wantDigest == "" {
+		return errors.New("checksum for " + assetName + " not found in " + checksumsAssetName)
+	}
+
+	sum := sha256.Sum256(data)
+	gotDigest := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(gotDigest, wantDigest) {
+		return errors.New("checksum mismatch for " + assetName)
+	}
+
+	return nil
+}
+
+// replaceExecutable atomically replaces the running binary with data. It writes to a
+// temporary file next to the current executable and renames over it, so a crash mid-write
+// never leaves the original binary in a partial state.
+func replaceExecutable(data []byte) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(executable)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(executable), filepath.Base(executable)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tempFile.Name(), info.Mode()); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), executable)
+}
+
+// checkForUpdate logs a one-line notice when a newer release is available on channel,
+// without downloading or applying it. It is used by the --check-update flag on the
+// regular commands; the selfupdate command itself performs the actual update.
+func checkForUpdate(log *logger.Logger, channel string) {
+	release, err := latestRelease(channel)
+	if err != nil {
+		log.D("Update check FAILED\n%s", err)
+		return
+	}
+
+	if isNewerVersion(versionString, release.TagName) {
+		log.I("acpublisher %s is available (currently running %s). Run \"selfupdate\" to update.", release.TagName, versionString)
+	} else {
+		log.D("acpublisher is up to date")
+	}
+}
+
+func handleSelfupdateCommand(cmd *command.Command) {
+	parseCommand(cmd)
+
+	channel := cmd.GetString(Channel)
+	if channel != ChannelStable && channel != ChannelPrerelease {
+		log.E("Channel must be %q or %q", ChannelStable, ChannelPrerelease)
+		os.Exit(1)
+	}
+
+	log.I("Checking %s releases...", channel)
+	release, err := latestRelease(channel)
+	if err != nil {
+		log.E("Checking for update FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	if !isNewerVersion(versionString, release.TagName) {
+		log.I("acpublisher is up to date (%s)", versionString)
+		return
+	}
+
+	log.I("New version available: %s", release.TagName)
+	if release.Body != "" {
+		logger.Println("%s", release.Body)
+	}
+
+	if cmd.GetBool(DryRun) {
+		return
+	}
+
+	assetName := platformAssetName()
+	asset := findAsset(release.Assets, assetName)
+	if asset == nil {
+		log.E("Release %s has no asset named %s", release.TagName, assetName)
+		os.Exit(1)
+	}
+
+	log.I("Downloading %s...", asset.Name)
+	data, err := downloadAsset(asset.BrowserDownloadUrl)
+	if err != nil {
+		log.E("Downloading update FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	log.I("Verifying checksum...")
+	if err := verifyChecksum(release.Assets, asset.Name, data); err != nil {
+		log.E("Verifying update FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	log.I("Installing update...")
+	if err := replaceExecutable(data); err != nil {
+		log.E("Installing update FAILED\n%s", err)
+		os.Exit(1)
+	}
+
+	log.I("Updated to %s", release.TagName)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		current string
+		tag     string
+		want    bool
+	}{
+		{current: "1.0.0", tag: "v1.1.0", want: true},
+		{current: "1.2.0", tag: "v1.3.0", want: true},
+		{current: "2.0.0", tag: "v1.9.9", want: false},
+		{current: "1.0.0", tag: "v1.0.0", want: false},
+		{current: "1.0", tag: "v1.0.0", want: false},
+		{current: "1.0.0", tag: "v1.0.0-rc.1", want: false},
+		{current: "1.0.0-rc.1", tag: "v1.0.0", want: true},
+		{current: "1.0.0-rc.1", tag: "v1.0.0-rc.2", want: true},
+		{current: "1.0.0", tag: "not-a-version", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+"_"+tt.tag, func(t *testing.T) {
+			if got := isNewerVersion(tt.current, tt.tag); got != tt.want {
+				t.Errorf("isNewerVersion(%q, %q) = %t, want %t", tt.current, tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("release binary contents")
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(digest + "  acpublisher_linux_amd64\n"))
+	}))
+	defer server.Close()
+
+	assets := []githubAsset{
+		{Name: checksumsAssetName, BrowserDownloadUrl: server.URL},
+	}
+
+	if err := verifyChecksum(assets, "acpublisher_linux_amd64", data); err != nil {
+		t.Fatalf("verifyChecksum() with a matching digest returned an error: %s", err)
+	}
+
+	if err := verifyChecksum(assets, "acpublisher_darwin_arm64", data); err == nil {
+		t.Fatal("verifyChecksum() for an asset missing from checksums.txt returned nil, want an error")
+	}
+
+	tamperedData := append([]byte{}, data...)
+	tamperedData[0] ^= 0xFF
+	if err := verifyChecksum(assets, "acpublisher_linux_amd64", tamperedData); err == nil {
+		t.Fatal("verifyChecksum() with mismatched data returned nil, want an error")
+	}
+
+	if err := verifyChecksum(nil, "acpublisher_linux_amd64", data); err == nil {
+		t.Fatal("verifyChecksum() with no checksums.txt asset returned nil, want an error")
+	}
+}
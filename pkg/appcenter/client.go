@@ -0,0 +1,174 @@
+// Package appcenter is a typed client for the AppCenter REST API used to create,
+// inspect and publish releases and their debug symbols.
+//
+// The request and response types in types.go are hand-written against the AppCenter
+// REST API (https://openapi.appcenter.ms); Client wraps them with a small,
+// context-aware method set.
+package appcenter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	defaultBaseURL    = "https://api.appcenter.ms"
+	defaultAPIVersion = "v0.1"
+)
+
+// Logger receives diagnostic output from a Client. *logger.Logger from this
+// repository's logger package already satisfies this interface.
+type Logger interface {
+	D(format string, args ...interface{})
+	V(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) D(string, ...interface{}) {}
+func (nopLogger) V(string, ...interface{}) {}
+
+// Client talks to the AppCenter REST API on behalf of a single API token.
+type Client struct {
+	baseURL    string
+	apiVersion string
+	apiToken   string
+	httpClient *http.Client
+	log        Logger
+}
+
+// Option customizes a Client returned by New.
+type Option func(*Client)
+
+// WithBaseURL overrides the default AppCenter API base URL, e.g. for testing.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) { c.baseURL = baseURL }
+}
+
+// WithAPIVersion overrides the default AppCenter API version path segment.
+func WithAPIVersion(apiVersion string) Option {
+	return func(c *Client) { c.apiVersion = apiVersion }
+}
+
+// WithRoundTripper sets the http.RoundTripper used for every request, e.g. to
+// inject tracing, custom TLS settings or a test transport.
+func WithRoundTripper(rt http.RoundTripper) Option {
+	return func(c *Client) { c.httpClient.Transport = rt }
+}
+
+// WithLogger sets the Logger requests and responses are logged to.
+func WithLogger(log Logger) Option {
+	return func(c *Client) { c.log = log }
+}
+
+// New creates a Client for the given API token.
+func New(apiToken string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    defaultBaseURL,
+		apiVersion: defaultAPIVersion,
+		apiToken:   apiToken,
+		httpClient: &http.Client{},
+		log:        nopLogger{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) url(pathSuffix string) string {
+	return c.baseURL + "/" + c.apiVersion + pathSuffix
+}
+
+// doRequest issues a JSON API request and returns the raw response body and status code.
+func (c *Client) doRequest(ctx context.Context, method string, url string, body interface{}) ([]byte, int, error) {
+	var bodyJson []byte
+	var err error
+
+	if body != nil {
+		bodyJson, err = json.Marshal(body)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(bodyJson))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header.Set("X-API-Token", c.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	c.log.V("--> %s %s %s", req.Method, req.URL.Path, req.Proto)
+	c.printHeaders(req.Header)
+	if bodyJson != nil {
+		c.log.V("%s", string(bodyJson))
+	}
+	c.log.V("--> END %s", req.Method)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	c.log.V("<-- %s %s", resp.Proto, resp.Status)
+	c.printHeaders(resp.Header)
+	if responseBody != nil {
+		c.log.V("%s", string(responseBody))
+	}
+	c.log.V("<-- END")
+
+	return responseBody, resp.StatusCode, nil
+}
+
+func (c *Client) printHeaders(header http.Header) {
+	for name, values := range header {
+		for _, value := range values {
+			c.log.V("%s: %s", name, value)
+		}
+	}
+}
+
+// jsonRequest issues a request and unmarshals its body into response, failing if
+// the response status does not match statusCode.
+func (c *Client) jsonRequest(ctx context.Context, method string, url string, body interface{}, statusCode int, response interface{}) error {
+	responseBody, status, err := c.doRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	if status != statusCode {
+		return errors.New(fmt.Sprintf("Unexpected response from server: %d", status))
+	}
+
+	return json.Unmarshal(responseBody, &response)
+}
+
+// statusRequest issues a request that returns no response body (e.g. delete),
+// failing if the response status does not match statusCode.
+func (c *Client) statusRequest(ctx context.Context, method string, url string, body interface{}, statusCode int) error {
+	_, status, err := c.doRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	if status != statusCode {
+		return errors.New(fmt.Sprintf("Unexpected response from server: %d", status))
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package appcenter
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) BeginReleaseUpload(ctx context.Context, appSlug string, buildType BuildType) (*ReleaseUploadBeginResponse, error) {
+	uploadUrl := c.url("/apps/" + appSlug + "/release_uploads")
+	if appCenterBuildType(buildType) != "" {
+		uploadUrl += "?build_type=" + appCenterBuildType(buildType)
+	}
+	c.log.D("Begin release upload")
+
+	request := ReleaseUploadBeginRequest{}
+	response := ReleaseUploadBeginResponse{}
+
+	err := c.jsonRequest(ctx, http.MethodPost, uploadUrl, &request, http.StatusCreated, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) CommitRelease(ctx context.Context, appSlug string, uploadId string) (*ReleaseUploadEndResponse, error) {
+	commitUrl := c.url("/apps/" + appSlug + "/release_uploads/" + uploadId)
+	c.log.D("Commit release %s", uploadId)
+
+	request := ReleaseUploadEndRequest{Status: COMMITTED}
+	response := ReleaseUploadEndResponse{}
+
+	err := c.jsonRequest(ctx, http.MethodPatch, commitUrl, &request, http.StatusOK, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) UpdateRelease(ctx context.Context, appSlug string, releaseId string, request ReleaseUpdateRequest) (*ReleaseUpdateResponse, error) {
+	updateUrl := c.url("/apps/" + appSlug + "/releases/" + releaseId)
+	c.log.D("Update release %s", releaseId)
+
+	response := ReleaseUpdateResponse{}
+
+	err := c.jsonRequest(ctx, http.MethodPatch, updateUrl, &request, http.StatusOK, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) PublishRelease(ctx context.Context, appSlug string, releaseId string, destinationType string, request ReleaseDestinationRequest) (*ReleaseDestinationResponse, error) {
+	publishUrl := c.url("/apps/" + appSlug + "/releases/" + releaseId + "/" + destinationType)
+	destination := request.Id
+	if destination == "" {
+		destination = request.Name
+	}
+	c.log.D("Publishing to %s %s", destinationType, destination)
+
+	response := ReleaseDestinationResponse{}
+
+	err := c.jsonRequest(ctx, http.MethodPost, publishUrl, &request, http.StatusCreated, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) GetRelease(ctx context.Context, appSlug string, releaseId string) (*ReleaseDetailsResponse, error) {
+	releaseUrl := c.url("/apps/" + appSlug + "/releases/" + releaseId)
+	c.log.D("Get release %s", releaseId)
+
+	response := ReleaseDetailsResponse{}
+
+	err := c.jsonRequest(ctx, http.MethodGet, releaseUrl, nil, http.StatusOK, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// ReleaseListFilter narrows the result of ListReleases to releases matching every
+// non-nil/non-empty field. A nil *ReleaseListFilter applies no extra filtering.
+type ReleaseListFilter struct {
+	Enabled           *bool
+	DistributionGroup string
+}
+
+// ListReleases lists releases of appSlug. By default only published releases are
+// returned, matching AppCenter's own default; includeDrafts also includes releases
+// that have not been published to any distribution group. filter, if non-nil, further
+// restricts the result to releases matching its Enabled and/or DistributionGroup.
+func (c *Client) ListReleases(ctx context.Context, appSlug string, includeDrafts bool, filter *ReleaseListFilter) ([]ReleaseListItem, error) {
+	listUrl := c.url("/apps/" + appSlug + "/releases")
+	if !includeDrafts {
+		listUrl += "?published_only=true"
+	}
+	c.log.D("List releases")
+
+	var releases []ReleaseListItem
+
+	err := c.jsonRequest(ctx, http.MethodGet, listUrl, nil, http.StatusOK, &releases)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterReleases(releases, filter), nil
+}
+
+func filterReleases(releases []ReleaseListItem, filter *ReleaseListFilter) []ReleaseListItem {
+	if filter == nil {
+		return releases
+	}
+
+	filtered := releases[:0]
+	for _, release := range releases {
+		if filter.Enabled != nil && release.Enabled != *filter.Enabled {
+			continue
+		}
+		if filter.DistributionGroup != "" && release.DistributionGroupName != filter.DistributionGroup {
+			continue
+		}
+		filtered = append(filtered, release)
+	}
+	return filtered
+}
+
+func (c *Client) DeleteRelease(ctx context.Context, appSlug string, releaseId string) error {
+	deleteUrl := c.url("/apps/" + appSlug + "/releases/" + releaseId)
+	c.log.D("Delete release %s", releaseId)
+
+	return c.statusRequest(ctx, http.MethodDelete, deleteUrl, nil, http.StatusOK)
+}
+
+func (c *Client) ListReleaseDownloads(ctx context.Context, appSlug string, releaseId string) ([]ReleaseDownload, error) {
+	downloadsUrl := c.url("/apps/" + appSlug + "/releases/" + releaseId + "/downloads")
+	c.log.D("List downloads for release %s", releaseId)
+
+	var response []ReleaseDownload
+
+	err := c.jsonRequest(ctx, http.MethodGet, downloadsUrl, nil, http.StatusOK, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,42 @@
+package appcenter
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+)
+
+func (c *Client) BeginSymbolUpload(ctx context.Context, appSlug string, version string, build string, fileName string, symbolType SymbolType) (*SymbolUploadBeginResponse, error) {
+	uploadUrl := c.url("/apps/" + appSlug + "/symbol_uploads")
+	c.log.D("Begin symbol upload")
+
+	request := SymbolUploadBeginRequest{
+		SymbolType: symbolType,
+		FileName:   filepath.Base(fileName),
+		Version:    version,
+		Build:      build,
+	}
+	response := SymbolUploadBeginResponse{}
+
+	err := c.jsonRequest(ctx, http.MethodPost, uploadUrl, &request, http.StatusOK, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) CommitSymbols(ctx context.Context, appSlug string, symbolUploadId string, status UploadStatus) (*SymbolUpload, error) {
+	commitUrl := c.url("/apps/" + appSlug + "/symbol_uploads/" + symbolUploadId)
+	c.log.D("Commit symbols %s", symbolUploadId)
+
+	request := SymbolUploadEndRequest{Status: status}
+	response := SymbolUpload{}
+
+	err := c.jsonRequest(ctx, http.MethodPatch, commitUrl, &request, http.StatusOK, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
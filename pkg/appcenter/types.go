@@ -0,0 +1,220 @@
+package appcenter
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+type BuildType string
+
+const (
+	BuildTypeAndroidApk  BuildType = "apk"
+	BuildTypeAndroidAab  BuildType = "aab"
+	BuildTypeIosIpa      BuildType = "ipa"
+	BuildTypeIosZip      BuildType = "zip"
+	BuildTypeWindowsMsi  BuildType = "msi"
+	BuildTypeWindowsAppx BuildType = "appxbundle"
+)
+
+// DetectBuildType picks the AppCenter build type from the binary's file extension.
+func DetectBuildType(path string) (BuildType, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".apk":
+		return BuildTypeAndroidApk, nil
+	case ".aab":
+		return BuildTypeAndroidAab, nil
+	case ".ipa":
+		return BuildTypeIosIpa, nil
+	case ".zip":
+		return BuildTypeIosZip, nil
+	case ".msi":
+		return BuildTypeWindowsMsi, nil
+	case ".appxbundle":
+		return BuildTypeWindowsAppx, nil
+	default:
+		return "", errors.New("unsupported binary extension: " + filepath.Ext(path))
+	}
+}
+
+// appCenterBuildType returns the value AppCenter expects as the release_uploads
+// `build_type` query parameter, or "" when the platform needs no hint.
+func appCenterBuildType(buildType BuildType) string {
+	if buildType == BuildTypeAndroidAab {
+		return "aab"
+	}
+	return ""
+}
+
+type UploadStatus string
+
+const (
+	COMMITTED UploadStatus = "committed"
+	ABORTED   UploadStatus = "aborted"
+)
+
+type SymbolType string
+
+const (
+	SymbolTypeApple      SymbolType = "Apple"
+	SymbolTypeJavascript SymbolType = "JavaScript"
+	SymbolTypeBreakpad   SymbolType = "Breakpad"
+	SymbolTypeAndroid    SymbolType = "AndroidProguard"
+	SymbolTypeUWP        SymbolType = "UWP"
+)
+
+// DetectSymbolType picks the AppCenter symbol type from the symbol file's name. Any
+// zip (e.g. "App.dSYM.zip", "symbols.zip") is treated as a dSYM archive, since AppCenter
+// has no other zip-packaged symbol type.
+func DetectSymbolType(path string) (SymbolType, error) {
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return SymbolTypeApple, nil
+	case strings.HasSuffix(name, ".sym"):
+		return SymbolTypeBreakpad, nil
+	case strings.HasSuffix(name, ".txt"):
+		return SymbolTypeAndroid, nil
+	default:
+		return "", errors.New("unsupported symbol file: " + path)
+	}
+}
+
+type SymbolUploadStatus string
+
+const (
+	SymbolUploadStatusCreated    SymbolUploadStatus = "created"
+	SymbolUploadStatusCommitted  SymbolUploadStatus = "committed"
+	SymbolUploadStatusAborted    SymbolUploadStatus = "aborted"
+	SymbolUploadStatusProcessing SymbolUploadStatus = "processing"
+	SymbolUploadStatusIndexed    SymbolUploadStatus = "indexed"
+	SymbolUploadStatusFailed     SymbolUploadStatus = "failed"
+)
+
+type ReleaseUploadBeginRequest struct {
+	ReleaseId    int    `json:"release_id,omitempty"`
+	BuildVersion string `json:"build_version,omitempty"`
+	BuildNumber  string `json:"build_number,omitempty"`
+}
+
+type ReleaseUploadBeginResponse struct {
+	UploadId    string `json:"upload_id"`
+	UploadUrl   string `json:"upload_url"`
+	AssetId     string `json:"asset_id,omitempty"`
+	AssetDomain string `json:"asset_domain,omitempty"`
+	AssetToken  string `json:"asset_token,omitempty"`
+}
+
+type ReleaseUploadEndRequest struct {
+	Status UploadStatus `json:"status"`
+}
+
+type ReleaseUploadEndResponse struct {
+	ReleaseId  string `json:"release_id,omitempty"`
+	ReleaseUrl string `json:"release_url,omitempty"`
+}
+
+type ReleaseUpdateRequest struct {
+	ReleaseNotes    string `json:"release_notes,omitempty"`
+	MandatoryUpdate bool   `json:"mandatory_update,omitempty"`
+	Destinations    *[]struct {
+		Id   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"destinations,omitempty"`
+	Build *struct {
+		BranchName    string `json:"branch_name,omitempty"`
+		CommitHash    string `json:"commit_hash,omitempty"`
+		CommitMessage string `json:"commit_message,omitempty"`
+	} `json:"build,omitempty"`
+	NotifyTesters bool `json:"notify_testers,omitempty"`
+	Metadata      *struct {
+		DsaSignature string `json:"dsa_signature,omitempty"`
+	} `json:"metadata,omitempty"`
+	// Assets references supplementary files (changelog, screenshots) by label and file
+	// name. AppCenter's release metadata only carries the reference, not the file bytes.
+	Assets *[]struct {
+		Label    string `json:"label,omitempty"`
+		FileName string `json:"file_name,omitempty"`
+	} `json:"assets,omitempty"`
+}
+
+type ReleaseUpdateResponse struct {
+	Enabled               bool   `json:"enabled,omitempty"`
+	MandatoryUpdate       bool   `json:"mandatory_update,omitempty"`
+	ReleaseNotes          string `json:"release_notes,omitempty"`
+	ProvisioningStatusUrl string `json:"provisioning_status_url,omitempty"`
+	Destinations          *[]struct {
+		Id   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"destinations,omitempty"`
+}
+
+// ReleaseDestinationRequest identifies a distribution group either by its id (a group
+// GUID) or, if Id is empty, by its Name.
+type ReleaseDestinationRequest struct {
+	Id              string `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	MandatoryUpdate bool   `json:"mandatory_update,omitempty"`
+	NotifyTesters   bool   `json:"notify_testers,omitempty"`
+}
+
+type ReleaseDestinationResponse struct {
+	Id                    string `json:"id"`
+	MandatoryUpdate       bool   `json:"mandatory_update"`
+	ProvisioningStatusUrl string `json:"provisioning_status_url,omitempty"`
+}
+
+type ReleaseDetailsResponse struct {
+	Id             int    `json:"id"`
+	AppName        string `json:"app_name"`
+	AppDisplayName string `json:"app_display_name"`
+	Version        string `json:"version"`
+	ShortVersion   string `json:"short_version"`
+	UploadedAt     string `json:"uploaded_at"`
+	AppIconUrl     string `json:"app_icon_url"`
+	Enabled        bool   `json:"enabled"`
+}
+
+type ReleaseListItem struct {
+	Id                    int    `json:"id"`
+	Version               string `json:"version"`
+	ShortVersion          string `json:"short_version"`
+	Enabled               bool   `json:"enabled"`
+	DistributionGroupName string `json:"distribution_group_name,omitempty"`
+	UploadedAt            string `json:"uploaded_at,omitempty"`
+}
+
+type ReleaseDownload struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type SymbolUploadBeginRequest struct {
+	SymbolType     SymbolType `json:"symbol_type"`
+	ClientCallback string     `json:"client_callback,omitempty"`
+	FileName       string     `json:"file_name,omitempty"`
+	Build          string     `json:"build,omitempty"`
+	Version        string     `json:"version,omitempty"`
+}
+
+type SymbolUploadBeginResponse struct {
+	SymbolUploadId string `json:"symbol_upload_id"`
+	UploadUrl      string `json:"upload_url"`
+	ExpirationDate string `json:"expiration_date"`
+}
+
+type SymbolUploadEndRequest struct {
+	Status UploadStatus `json:"status"`
+}
+
+type SymbolUpload struct {
+	SymbolUploadId string `json:"symbol_upload_id"`
+	AppId          string `json:"app_id"`
+	User           *struct {
+		Email       string `json:"email,omitempty"`
+		DisplayName string `json:"display_name,omitempty"`
+	} `json:"user,omitempty"`
+	Status     SymbolUploadStatus `json:"status"`
+	SymbolType SymbolType         `json:"symbol_type"`
+}
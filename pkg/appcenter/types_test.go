@@ -0,0 +1,73 @@
+package appcenter
+
+import "testing"
+
+func TestDetectBuildType(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    BuildType
+		wantErr bool
+	}{
+		{path: "app.apk", want: BuildTypeAndroidApk},
+		{path: "app.aab", want: BuildTypeAndroidAab},
+		{path: "App.IPA", want: BuildTypeIosIpa},
+		{path: "archive.zip", want: BuildTypeIosZip},
+		{path: "setup.msi", want: BuildTypeWindowsMsi},
+		{path: "package.appxbundle", want: BuildTypeWindowsAppx},
+		{path: "build/app.apk", want: BuildTypeAndroidApk},
+		{path: "readme.txt", wantErr: true},
+		{path: "noextension", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := DetectBuildType(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DetectBuildType(%q) = %q, nil; want error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectBuildType(%q) returned unexpected error: %s", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectBuildType(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectSymbolType(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    SymbolType
+		wantErr bool
+	}{
+		{path: "App.dSYM.zip", want: SymbolTypeApple},
+		{path: "symbols.zip", want: SymbolTypeApple},
+		{path: "crash.sym", want: SymbolTypeBreakpad},
+		{path: "mapping.txt", want: SymbolTypeAndroid},
+		{path: "build/mapping.txt", want: SymbolTypeAndroid},
+		{path: "mapping.proguard", wantErr: true},
+		{path: "noextension", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := DetectSymbolType(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DetectSymbolType(%q) = %q, nil; want error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectSymbolType(%q) returned unexpected error: %s", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectSymbolType(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
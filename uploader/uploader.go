@@ -0,0 +1,271 @@
+// Package uploader streams releases and symbol files to AppCenter without buffering
+// the whole file in memory, and retries transient failures with exponential backoff.
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+)
+
+// Options configures chunking, parallelism and retry behaviour of an upload.
+type Options struct {
+	// ChunkSize is the size in bytes of each block staged to Azure Blob storage.
+	ChunkSize int64
+	// Parallelism is the number of blocks staged concurrently.
+	Parallelism int
+	// MaxRetries is the number of retries attempted for a failing request or block,
+	// on top of the initial attempt.
+	MaxRetries int
+}
+
+// DefaultOptions holds the default upload settings. Only its ChunkSize and Parallelism
+// are substituted for non-positive values (see sanitizeOptions); a MaxRetries of 0 means
+// no retries.
+var DefaultOptions = Options{
+	ChunkSize:   4 * 1024 * 1024,
+	Parallelism: 4,
+	MaxRetries:  5,
+}
+
+// sanitizeOptions replaces any non-positive ChunkSize or Parallelism with the
+// DefaultOptions value, so a zero or negative value can't divide-by-zero in
+// stageBlocks or deadlock the staging semaphore.
+func sanitizeOptions(opts Options) Options {
+	if opts.ChunkSize < 1 {
+		opts.ChunkSize = DefaultOptions.ChunkSize
+	}
+	if opts.Parallelism < 1 {
+		opts.Parallelism = DefaultOptions.Parallelism
+	}
+	return opts
+}
+
+// UploadRelease streams file to uploadUrl as an AppCenter release upload, retrying
+// the request with exponential backoff on transient (429/5xx) failures.
+func UploadRelease(ctx context.Context, uploadUrl string, file *os.File, opts Options) error {
+	opts = sanitizeOptions(opts)
+
+	return withRetry(ctx, opts.MaxRetries, func() error {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+
+		go func() {
+			fw, err := mw.CreateFormFile("ipa", file.Name())
+			if err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(fw, file); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			_ = pw.CloseWithError(mw.Close())
+		}()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadUrl, pr)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = ioutil.ReadAll(resp.Body)
+
+		return statusError(resp)
+	})
+}
+
+// UploadSymbols stages file to the Azure Blob destination at uploadUrl in ChunkSize
+// blocks, up to Parallelism at a time, before committing the block list. Failed
+// blocks and the final commit are retried independently with exponential backoff.
+func UploadSymbols(ctx context.Context, uploadUrl string, file *os.File, opts Options) error {
+	opts = sanitizeOptions(opts)
+
+	parsedUrl, err := url.Parse(uploadUrl)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	pipeline := azblob.NewPipeline(azblob.NewAnonymousCredential(), azblob.PipelineOptions{})
+	blockBlobURL := azblob.NewBlockBlobURL(*parsedUrl, pipeline)
+
+	blockIds, err := stageBlocks(ctx, blockBlobURL, file, info.Size(), opts)
+	if err != nil {
+		return err
+	}
+
+	return withRetry(ctx, opts.MaxRetries, func() error {
+		_, err := blockBlobURL.CommitBlockList(ctx, blockIds, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.AccessTierNone, nil, azblob.ClientProvidedKeyOptions{})
+		return err
+	})
+}
+
+func stageBlocks(ctx context.Context, blockBlobURL azblob.BlockBlobURL, file *os.File, size int64, opts Options) ([]string, error) {
+	blockCount := int((size + opts.ChunkSize - 1) / opts.ChunkSize)
+	blockIds := make([]string, blockCount)
+
+	sem := make(chan struct{}, opts.Parallelism)
+	errs := make(chan error, blockCount)
+	var wg sync.WaitGroup
+
+	for i := 0; i < blockCount; i++ {
+		offset := int64(i) * opts.ChunkSize
+		length := opts.ChunkSize
+		if offset+length > size {
+			length = size - offset
+		}
+		blockIds[i] = blockId(i)
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(index int, offset int64, length int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			buf := make([]byte, length)
+			if _, err := file.ReadAt(buf, offset); err != nil && err != io.EOF {
+				errs <- err
+				return
+			}
+
+			errs <- withRetry(ctx, opts.MaxRetries, func() error {
+				_, err := blockBlobURL.StageBlock(ctx, blockIds[index], bytes.NewReader(buf), azblob.LeaseAccessConditions{}, nil, azblob.ClientProvidedKeyOptions{})
+				return err
+			})
+		}(i, offset, length)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return blockIds, nil
+}
+
+// blockId returns a stable, ordered base64 block id for the block at index i, as
+// required by Azure to preserve block order in the committed blob.
+func blockId(i int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%010d", i)))
+}
+
+// withRetry runs fn, retrying up to maxRetries times with exponential backoff and
+// jitter between attempts. It stops early if ctx is cancelled, or if fn fails with a
+// permanentStatusError, which retrying cannot fix.
+func withRetry(ctx context.Context, maxRetries int, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		var permanentErr *permanentStatusError
+		if errors.As(err, &permanentErr) {
+			return err
+		}
+		if attempt >= maxRetries {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+		}
+
+		select {
+		case <-time.After(backoff(attempt, retryAfterFromError(err))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	base := time.Duration(math.Pow(2, float64(attempt))) * 500 * time.Millisecond
+	jitter := time.Duration(rand.Int63n(int64(base)))
+	return base + jitter
+}
+
+// retryableStatusError carries the Retry-After delay the server asked for, if any.
+// It is only returned for responses worth retrying (429/5xx).
+type retryableStatusError struct {
+	statusCode int
+	retryAfter time.Duration
+}
+
+func (e *retryableStatusError) Error() string {
+	return fmt.Sprintf("unexpected response from server: %d", e.statusCode)
+}
+
+// permanentStatusError marks a non-2xx response that retrying cannot fix, e.g. 400/401/403/404.
+type permanentStatusError struct {
+	statusCode int
+}
+
+func (e *permanentStatusError) Error() string {
+	return fmt.Sprintf("unexpected response from server: %d", e.statusCode)
+}
+
+func retryAfterFromError(err error) time.Duration {
+	var statusErr *retryableStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.retryAfter
+	}
+	return 0
+}
+
+// statusError classifies resp's status: nil for 2xx, a retryableStatusError for 429/5xx,
+// and a permanentStatusError for any other non-2xx status.
+func statusError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return &retryableStatusError{statusCode: resp.StatusCode, retryAfter: retryAfterDuration(resp.Header.Get("Retry-After"))}
+	}
+	return &permanentStatusError{statusCode: resp.StatusCode}
+}
+
+func retryAfterDuration(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
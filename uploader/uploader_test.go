@@ -0,0 +1,114 @@
+package uploader
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBlockId(t *testing.T) {
+	first := blockId(0)
+	second := blockId(1)
+
+	if first == "" {
+		t.Fatal("blockId(0) returned an empty string")
+	}
+	if first == second {
+		t.Fatalf("blockId(0) and blockId(1) produced the same id %q", first)
+	}
+	if got := blockId(0); got != first {
+		t.Fatalf("blockId(0) is not stable: got %q, want %q", got, first)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if got := backoff(0, 5*time.Second); got != 5*time.Second {
+		t.Errorf("backoff(0, 5s) = %s, want the Retry-After value of 5s", got)
+	}
+
+	for attempt := 0; attempt < 4; attempt++ {
+		base := time.Duration(1<<uint(attempt)) * 500 * time.Millisecond
+		got := backoff(attempt, 0)
+		if got < base || got >= 2*base {
+			t.Errorf("backoff(%d, 0) = %s, want in [%s, %s)", attempt, got, base, 2*base)
+		}
+	}
+}
+
+func TestRetryAfterDuration(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "", want: 0},
+		{header: "120", want: 120 * time.Second},
+		{header: "not-a-number-or-date", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := retryAfterDuration(tt.header); got != tt.want {
+				t.Errorf("retryAfterDuration(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		wantRetryable bool
+		wantPermanent bool
+	}{
+		{name: "ok", statusCode: http.StatusOK},
+		{name: "created", statusCode: http.StatusCreated},
+		{name: "too many requests", statusCode: http.StatusTooManyRequests, wantRetryable: true},
+		{name: "internal server error", statusCode: http.StatusInternalServerError, wantRetryable: true},
+		{name: "bad gateway", statusCode: http.StatusBadGateway, wantRetryable: true},
+		{name: "bad request", statusCode: http.StatusBadRequest, wantPermanent: true},
+		{name: "unauthorized", statusCode: http.StatusUnauthorized, wantPermanent: true},
+		{name: "not found", statusCode: http.StatusNotFound, wantPermanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			err := statusError(resp)
+
+			switch {
+			case !tt.wantRetryable && !tt.wantPermanent:
+				if err != nil {
+					t.Fatalf("statusError(%d) = %v, want nil", tt.statusCode, err)
+				}
+			case tt.wantRetryable:
+				var retryableErr *retryableStatusError
+				if !errors.As(err, &retryableErr) {
+					t.Fatalf("statusError(%d) = %v, want a *retryableStatusError", tt.statusCode, err)
+				}
+			case tt.wantPermanent:
+				var permanentErr *permanentStatusError
+				if !errors.As(err, &permanentErr) {
+					t.Fatalf("statusError(%d) = %v, want a *permanentStatusError", tt.statusCode, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusErrorHonorsRetryAfter(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": []string{"30"}},
+	}
+
+	err := statusError(resp)
+	var retryableErr *retryableStatusError
+	if !errors.As(err, &retryableErr) {
+		t.Fatalf("statusError(503) = %v, want a *retryableStatusError", err)
+	}
+	if retryableErr.retryAfter != 30*time.Second {
+		t.Errorf("retryAfter = %s, want 30s", retryableErr.retryAfter)
+	}
+}